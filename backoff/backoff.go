@@ -0,0 +1,76 @@
+// Package backoff implements the jittered exponential retry policy kube-scheduler uses for
+// kubernetes API calls and outbound notifier requests. Backoff is modeled on
+// k8s.io/apimachinery/pkg/util/wait.Backoff.
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff describes a jittered exponential retry policy.
+type Backoff struct {
+	// Duration is the base delay before the first retry.
+	Duration time.Duration
+	// Factor multiplies Duration after every attempt. Zero or one never grows the delay.
+	Factor float64
+	// Jitter adds up to Jitter*Duration of additional random delay on top of each step, so a burst
+	// of failures doesn't retry in lockstep.
+	Jitter float64
+	// Cap is the maximum delay between attempts, regardless of Factor.
+	Cap time.Duration
+	// Steps is the maximum number of attempts, including the first.
+	Steps int
+}
+
+// Default is the policy autoRetry used before this package existed: 3 attempts, 1s apart, no
+// growth or jitter. It is the fallback whenever a scheduler.Job does not configure its own.
+var Default = Backoff{Duration: time.Second, Factor: 1, Steps: 3}
+
+// step returns the delay to sleep before the next attempt and advances b's internal state.
+func (b *Backoff) step() time.Duration {
+	duration := b.Duration
+
+	if b.Jitter > 0 {
+		duration += time.Duration(rand.Float64() * b.Jitter * float64(duration))
+	}
+
+	if b.Cap > 0 && duration > b.Cap {
+		duration = b.Cap
+	}
+
+	if b.Factor > 0 {
+		b.Duration = time.Duration(float64(b.Duration) * b.Factor)
+	}
+
+	return duration
+}
+
+// Retry calls fn until it succeeds, isRetriable returns false for its error, or b's attempts are
+// exhausted - whichever comes first. It sleeps b's jittered delay between attempts.
+func (b Backoff) Retry(fn func() (interface{}, error), isRetriable func(error) bool) (interface{}, error) {
+	steps := b.Steps
+	if steps < 1 {
+		steps = 1
+	}
+
+	var thing interface{}
+	var err error
+
+	for attempt := 0; attempt < steps; attempt++ {
+		thing, err = fn()
+		if err == nil {
+			return thing, nil
+		}
+
+		if !isRetriable(err) {
+			return nil, err
+		}
+
+		if attempt < steps-1 {
+			time.Sleep(b.step())
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,87 @@
+package backoff
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Config is the on-disk (YAML/JSON) shape of a per-job retry policy, e.g. a schedule.yml job's
+// `backoff:` block or a ScheduledJob's spec.backoff. A zero field falls back to Default's
+// corresponding field.
+type Config struct {
+	Duration    time.Duration `json:"duration,omitempty"`
+	Factor      float64       `yaml:"factor" json:"factor,omitempty"`
+	Jitter      float64       `yaml:"jitter" json:"jitter,omitempty"`
+	Cap         time.Duration `json:"cap,omitempty"`
+	MaxAttempts int           `yaml:"max_attempts" json:"maxAttempts,omitempty"`
+}
+
+// configYAML mirrors Config with Duration and Cap as strings. yaml.v2 otherwise decodes
+// time.Duration as a plain int64 of nanoseconds, so a human duration like "30s" in schedule.yml
+// would fail to parse.
+type configYAML struct {
+	Duration    string  `yaml:"duration"`
+	Factor      float64 `yaml:"factor"`
+	Jitter      float64 `yaml:"jitter"`
+	Cap         string  `yaml:"cap"`
+	MaxAttempts int     `yaml:"max_attempts"`
+}
+
+// UnmarshalYAML lets Duration and Cap be written as human durations, e.g. "30s" or "5m", instead
+// of raw nanosecond integers.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw configYAML
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	c.Factor = raw.Factor
+	c.Jitter = raw.Jitter
+	c.MaxAttempts = raw.MaxAttempts
+
+	if raw.Duration != "" {
+		d, err := time.ParseDuration(raw.Duration)
+		if err != nil {
+			return errors.Wrap(err, "Invalid backoff duration")
+		}
+		c.Duration = d
+	}
+
+	if raw.Cap != "" {
+		d, err := time.ParseDuration(raw.Cap)
+		if err != nil {
+			return errors.Wrap(err, "Invalid backoff cap")
+		}
+		c.Cap = d
+	}
+
+	return nil
+}
+
+// Backoff builds a Backoff from c, falling back to Default for any zero field. A nil Config
+// returns Default unchanged.
+func (c *Config) Backoff() Backoff {
+	b := Default
+	if c == nil {
+		return b
+	}
+
+	if c.Duration > 0 {
+		b.Duration = c.Duration
+	}
+	if c.Factor > 0 {
+		b.Factor = c.Factor
+	}
+	if c.Jitter > 0 {
+		b.Jitter = c.Jitter
+	}
+	if c.Cap > 0 {
+		b.Cap = c.Cap
+	}
+	if c.MaxAttempts > 0 {
+		b.Steps = c.MaxAttempts
+	}
+
+	return b
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/wearemolecule/kube-scheduler/kubernetes"
+	"github.com/wearemolecule/kube-scheduler/scheduler"
+)
+
+// serveMetrics runs for the lifetime of the process, exposing /metrics (Prometheus),
+// /healthz (liveness), and /readyz (readiness). Only started in InProcess mode, since
+// NativeCronJob mode exits right after a single SyncCronJobs reconcile.
+func serveMetrics(addr string, kubeClient kubernetes.ClientInterface, schedulerClient scheduler.ClientInterface, livenessMaxTickAge time.Duration) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", livenessHandler(schedulerClient, livenessMaxTickAge))
+	mux.HandleFunc("/readyz", readinessHandler(kubeClient))
+
+	glog.Infof("Serving /metrics, /healthz, and /readyz on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		glog.Error(errors.Wrap(err, "Metrics server stopped"))
+	}
+}
+
+// livenessHandler fails once the cron runner has gone longer than livenessMaxTickAge without
+// ticking any scheduled job - a sign the in-process cron loop has wedged.
+func livenessHandler(schedulerClient scheduler.ClientInterface, livenessMaxTickAge time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if age := time.Since(schedulerClient.LastTick()); age > livenessMaxTickAge {
+			http.Error(w, fmt.Sprintf("cron runner has not ticked in %s (max %s)", age, livenessMaxTickAge), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// readinessHandler fails while the kube client cannot list Jobs, e.g. during an API server
+// outage or before credentials are valid.
+func readinessHandler(kubeClient kubernetes.ClientInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := kubeClient.CanListJobs(); err != nil {
+			http.Error(w, fmt.Sprintf("unable to list jobs: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
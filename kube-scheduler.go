@@ -6,10 +6,12 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"github.com/wearemolecule/kube-scheduler/kubernetes"
+	"github.com/wearemolecule/kube-scheduler/metrics"
 	"github.com/wearemolecule/kube-scheduler/notifier"
 	"github.com/wearemolecule/kube-scheduler/scheduler"
 )
@@ -18,12 +20,64 @@ var (
 	kubernetesConfigPath string
 	scheduleConfigPath   string
 	scheduleConfigName   string
+	schedulerMode        string
+	jobSource            string
+
+	leaderElect              bool
+	leaderElectLeaseDuration time.Duration
+	leaderElectRenewDeadline time.Duration
+	leaderElectResourceName  string
+
+	metricsAddr        string
+	livenessMaxTickAge time.Duration
 )
 
 func init() {
 	flag.StringVar(&scheduleConfigName, "schedule-name", "schedule.yml", "name of schedule config file (defaults to schedule.yml)")
 	flag.StringVar(&scheduleConfigPath, "schedule-path", ".", "absolute path to schedule yaml (defaults to current dir)")
 	flag.StringVar(&kubernetesConfigPath, "kube-config-path", "", "absolute path to kubernetes credentials dir")
+	flag.StringVar(&schedulerMode, "mode", "in-process", "scheduling mode: 'in-process' runs an in-process cron loop, 'native-cronjob' reconciles schedule.yml into kubernetes CronJob resources")
+	flag.StringVar(&jobSource, "job-source", "yaml", "where the job list is loaded from: 'yaml' reads schedule.yml, 'crd' watches ScheduledJob custom resources")
+	flag.BoolVar(&leaderElect, "leader-elect", false, "run multiple kube-scheduler replicas as an active/standby set, coordinated by a Lease in the scheduler's namespace")
+	flag.DurationVar(&leaderElectLeaseDuration, "leader-elect-lease-duration", 15*time.Second, "duration non-leader candidates wait before attempting to acquire the leader election Lease")
+	flag.DurationVar(&leaderElectRenewDeadline, "leader-elect-renew-deadline", 10*time.Second, "duration the leader retries refreshing its Lease before giving up and releasing leadership")
+	flag.StringVar(&leaderElectResourceName, "leader-elect-resource-name", "kube-scheduler", "name of the Lease object used for leader election")
+	flag.StringVar(&metricsAddr, "metrics-addr", ":9090", "address to serve /metrics, /healthz, and /readyz on")
+	flag.DurationVar(&livenessMaxTickAge, "liveness-max-tick-age", 60*time.Minute, "in-process mode: /healthz fails once the cron runner has gone longer than this without ticking")
+}
+
+func scheduleMode() scheduler.Mode {
+	if schedulerMode == "native-cronjob" {
+		return scheduler.NativeCronJob
+	}
+
+	return scheduler.InProcess
+}
+
+func newSource(namespace string) scheduler.Source {
+	if jobSource == "crd" {
+		return &scheduler.CRDSource{Namespace: namespace, KubeConfigPath: kubernetesConfigPath}
+	}
+
+	return scheduler.YAMLFileSource{Path: scheduleConfigPath, Name: scheduleConfigName}
+}
+
+// notifierConfigs pulls the notifiers: block out of source if it carries one (today, only
+// YAMLFileSource does). A Source without one simply has no additional named backends beyond the
+// defaults every job already reaches.
+func notifierConfigs(source scheduler.Source) map[string]notifier.BackendConfig {
+	notifierSource, ok := source.(scheduler.NotifierSource)
+	if !ok {
+		return nil
+	}
+
+	configs, err := notifierSource.NotifierConfigs()
+	if err != nil {
+		glog.Error(errors.Wrap(err, "Unable to load notifier backend configs"))
+		return nil
+	}
+
+	return configs
 }
 
 func main() {
@@ -31,8 +85,9 @@ func main() {
 	birthCry()
 
 	namespace := os.Getenv("SCHEDULER_NAMESPACE")
+	source := newSource(namespace)
 
-	notifier := notifier.NewClient(namespace)
+	notifier := notifier.NewClient(namespace, notifierConfigs(source))
 
 	kubernetesClient, err := kubernetes.NewClient(kubernetesConfigPath, scheduleConfigPath)
 	if err != nil {
@@ -40,15 +95,61 @@ func main() {
 		return
 	}
 
-	scheduler, err := scheduler.NewClient(scheduleConfigPath, scheduleConfigName)
+	mode := scheduleMode()
+	nativeCronJob := mode == scheduler.NativeCronJob
+
+	scheduler, err := scheduler.NewClient(source, mode)
 	if err != nil {
 		notifier.Notify("Failed to create scheduler", err)
 		return
 	}
 
-	done := make(chan int)
+	if nativeCronJob {
+		if err := kubernetesClient.SyncCronJobs(scheduler.JobList()); err != nil {
+			notifier.Notify("Failed to sync cron jobs", err)
+		}
+		return
+	}
+
+	go serveMetrics(metricsAddr, kubernetesClient, scheduler, livenessMaxTickAge)
+
+	// Buffered so the signal handler's done <- 1 below never blocks: in leader-elect mode, schedule
+	// only starts from OnStartedLeading, and a replica that stays a follower until interrupted would
+	// otherwise have no reader on done and hang instead of shutting down.
+	done := make(chan int, 1)
+	startScheduling := func() { go schedule(scheduler, kubernetesClient, notifier, done) }
+
+	if leaderElect {
+		identity, err := os.Hostname()
+		if err != nil {
+			notifier.Notify("Failed to determine leader election identity", err)
+			return
+		}
+
+		cfg := kubernetes.LeaderElectionConfig{
+			Namespace:     namespace,
+			ResourceName:  leaderElectResourceName,
+			Identity:      identity,
+			LeaseDuration: leaderElectLeaseDuration,
+			RenewDeadline: leaderElectRenewDeadline,
+			RetryPeriod:   leaderElectRenewDeadline / 3,
+		}
 
-	go schedule(scheduler, kubernetesClient, notifier, done)
+		go func() {
+			err := kubernetesClient.RunLeaderElection(cfg, startScheduling, func() {
+				glog.Info("Lost leadership, draining running jobs and exiting so the deployment restarts us as a follower")
+				scheduler.Stop()
+				scheduler.Drain()
+				os.Exit(1)
+			})
+			if err != nil {
+				notifier.Notify("Leader election failed", err)
+				os.Exit(1)
+			}
+		}()
+	} else {
+		startScheduling()
+	}
 
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, os.Interrupt, os.Kill)
@@ -62,6 +163,30 @@ func schedule(
 	kubeClient kubernetes.ClientInterface,
 	notifier notifier.ClientInterface,
 	done chan int,
+) {
+	registerJobs(scheduler, kubeClient, notifier)
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-scheduler.Changes():
+			glog.Info("Job list changed, reloading cron entries")
+			scheduler.Reset()
+			registerJobs(scheduler, kubeClient, notifier)
+			scheduler.Start()
+		}
+	}
+}
+
+// registerJobs adds every job in scheduler.JobList() to the cron runner. It is called once at
+// startup and again after every scheduler.Changes() event, since Reset throws away prior entries.
+func registerJobs(
+	scheduler scheduler.ClientInterface,
+	kubeClient kubernetes.ClientInterface,
+	notifier notifier.ClientInterface,
 ) {
 	for name, job := range scheduler.JobList() {
 		if job.Cron == "" {
@@ -77,37 +202,38 @@ func schedule(
 			glog.Info("Running job ", nameCopy)
 			defer glog.Info("Finished job ", nameCopy)
 
-			if err := run(nameCopy, jobCopy, kubeClient, scheduler); err != nil {
-				notifier.Notify(fmt.Sprintf("Unable to create/run job %s", nameCopy), err)
+			start := time.Now()
+			skipped, err := run(nameCopy, jobCopy, kubeClient, scheduler)
+			if skipped {
+				return
+			}
+			if err != nil {
+				notifier.Notify(fmt.Sprintf("Unable to create/run job %s", nameCopy), err, jobCopy.Notify...)
+				return
 			}
-		})
-	}
-
-	scheduler.Start()
-	defer scheduler.Stop()
 
-	for {
-		select {
-		case <-done:
-			return
-		}
+			notifier.NotifySuccess(fmt.Sprintf("Job %s finished successfully", nameCopy), time.Since(start), jobCopy.Notify...)
+		})
 	}
 }
 
-func run(name string, job scheduler.Job, kubeClient kubernetes.ClientInterface, scheduler scheduler.ClientInterface) error {
+// run reports skipped=true when the job was already running and this tick was dropped, so the
+// caller can tell that apart from a completed, successful run - both otherwise return a nil err.
+func run(name string, job scheduler.Job, kubeClient kubernetes.ClientInterface, scheduler scheduler.ClientInterface) (skipped bool, err error) {
 	if scheduler.Running(name, job) {
 		glog.Warningf("Unable to start %s becuase it is already running", name)
-		return nil
+		metrics.CronMissedTicksTotal.Inc()
+		return true, nil
 	}
 
 	scheduler.StartJob(name, job)
-	defer scheduler.FinishJob(name, job)
+	defer func() { scheduler.FinishJob(name, job, err) }()
 
-	if err := kubeClient.RunJob(name, job); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Unable to create job %s", name))
+	if err = kubeClient.RunJob(name, job); err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to create job %s", name))
 	}
 
-	return nil
+	return false, nil
 }
 
 var (
@@ -0,0 +1,236 @@
+package kubernetes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/wearemolecule/kube-scheduler/scheduler"
+	"k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// ownedByLabel marks every batch/v1.Job RunJob creates, so the Controller's informer can be
+// scoped to jobs this scheduler owns instead of every Job in the cluster.
+const ownedByLabel = "kube-scheduler.molecule.io/owned-by"
+
+// Controller watches batch/v1.Job objects with a shared informer and drains observed changes
+// through a rate-limited workqueue. This replaces the previous pattern of blocking one goroutine
+// per in-flight job in a single watch.Interface's ResultChan() - a dropped watch connection there
+// (common on long-running jobs) caused RunJob to return nil as though the job had succeeded. Here
+// a dropped watch only triggers informer re-list, and syncJob always re-derives state from the
+// latest object in the local store.
+type Controller struct {
+	client   *client
+	informer cache.SharedIndexInformer
+	queue    workqueue.RateLimitingInterface
+
+	mutex   sync.Mutex
+	pending map[string]*pendingJob
+}
+
+// pendingJob tracks a RunJob call that is blocked waiting for its batch/v1.Job to finish.
+type pendingJob struct {
+	name   string
+	job    scheduler.Job
+	result chan error
+}
+
+func newController(c *client) *Controller {
+	ctrl := &Controller{
+		client:  c,
+		queue:   workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		pending: make(map[string]*pendingJob),
+	}
+
+	ctrl.informer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = ownedBySelector().String()
+				return c.client.BatchV1().Jobs(metav1.NamespaceAll).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = ownedBySelector().String()
+				options.Watch = true
+				return c.client.BatchV1().Jobs(metav1.NamespaceAll).Watch(context.Background(), options)
+			},
+		},
+		&v1.Job{},
+		30*time.Second,
+		cache.Indexers{},
+	)
+
+	ctrl.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctrl.enqueue,
+		UpdateFunc: func(old, new interface{}) { ctrl.enqueue(new) },
+		DeleteFunc: ctrl.enqueue,
+	})
+
+	return ctrl
+}
+
+func ownedBySelector() labels.Selector {
+	return labels.Set{ownedByLabel: "true"}.AsSelector()
+}
+
+// Run starts the informer and a pool of workers that drain the workqueue. It blocks until stopCh
+// is closed.
+func (ctrl *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.queue.ShutDown()
+
+	go ctrl.informer.Run(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.informer.HasSynced) {
+		glog.Error("Timed out waiting for kubernetes job informer cache to sync")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// SubmitJob creates the batch/v1.Job backing the given scheduler.Job, labels it as owned by this
+// controller, and blocks until the informer observes it reach a terminal condition.
+func (ctrl *Controller) SubmitJob(name string, job scheduler.Job) error {
+	kubeJob, err := ctrl.client.readJobTemplate(job)
+	if err != nil {
+		return err
+	}
+
+	glog.V(2).Infof("For %s found args: %v", name, job.Args)
+	glog.V(2).Infof("For %s found namespace: %s", name, job.Namespace)
+	firstContainer := &kubeJob.Spec.Template.Spec.Containers[0]
+	firstContainer.Args = job.Args
+	if job.Image != "" {
+		firstContainer.Image = job.Image
+	}
+	kubeJob.ObjectMeta.Namespace = job.Namespace
+	if kubeJob.ObjectMeta.Labels == nil {
+		kubeJob.ObjectMeta.Labels = map[string]string{}
+	}
+	kubeJob.ObjectMeta.Labels[ownedByLabel] = "true"
+
+	policy := backoffFor(job)
+
+	// Register pending before createJob, keyed the same way the informer keys its workqueue
+	// entries (namespace/name - the template's name is fixed, not generated, so it's known up
+	// front). A fast job can reach a terminal condition before createJob even returns; registering
+	// after the fact risks syncJob finding no pending entry and Forgetting the key, which would
+	// only unblock SubmitJob on the next 30s resync (or never, if a delete arrives first).
+	key := pendingKey(kubeJob.Namespace, kubeJob.Name)
+	pending := &pendingJob{name: name, job: job, result: make(chan error, 1)}
+	ctrl.mutex.Lock()
+	ctrl.pending[key] = pending
+	ctrl.mutex.Unlock()
+
+	clusterJob, err := ctrl.client.createJob(kubeJob, job.Namespace, policy)
+	if err != nil {
+		ctrl.mutex.Lock()
+		delete(ctrl.pending, key)
+		ctrl.mutex.Unlock()
+		return errors.Wrap(err, "Error creating kubernetes job")
+	}
+	defer ctrl.client.deleteJob(clusterJob, job.Namespace, policy)
+
+	return <-pending.result
+}
+
+// pendingKey matches the "namespace/name" format cache.DeletionHandlingMetaNamespaceKeyFunc
+// produces, so pending can be looked up directly by the workqueue key in syncJob without
+// re-deriving it and without colliding across namespaces for same-named jobs.
+func pendingKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func (ctrl *Controller) runWorker() {
+	for ctrl.processNextWorkItem() {
+	}
+}
+
+func (ctrl *Controller) processNextWorkItem() bool {
+	key, shutdown := ctrl.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer ctrl.queue.Done(key)
+
+	if err := ctrl.syncJob(key.(string)); err != nil {
+		glog.Errorf("Error syncing job %s, requeuing: %v", key, err)
+		ctrl.queue.AddRateLimited(key)
+		return true
+	}
+
+	ctrl.queue.Forget(key)
+	return true
+}
+
+// syncJob advances a single job's observed state. It is idempotent and safe to call any number of
+// times for the same key - it only acts once a pendingJob is waiting on the result and the job has
+// reached a terminal condition.
+func (ctrl *Controller) syncJob(key string) error {
+	obj, exists, err := ctrl.informer.GetStore().GetByKey(key)
+	if err != nil {
+		return errors.Wrap(err, "Error fetching job from informer store")
+	}
+
+	if !exists {
+		// The job was deleted (or GC'd) out from under us. DeleteFunc still enqueues this key, but
+		// with no object left to read a terminal condition from; without this, a pending SubmitJob
+		// call blocks on <-pending.result forever and this job can never run again.
+		ctrl.mutex.Lock()
+		pending, ok := ctrl.pending[key]
+		ctrl.mutex.Unlock()
+		if ok {
+			ctrl.finish(key, pending, errors.New("job was deleted before completing"))
+		}
+		return nil
+	}
+
+	job := obj.(*v1.Job)
+
+	ctrl.mutex.Lock()
+	pending, ok := ctrl.pending[key]
+	ctrl.mutex.Unlock()
+	if !ok || len(job.Status.Conditions) == 0 {
+		return nil
+	}
+
+	condition := job.Status.Conditions[0]
+	switch condition.Type {
+	case v1.JobComplete:
+		ctrl.finish(key, pending, nil)
+	case v1.JobFailed:
+		ctrl.finish(key, pending, errors.New(condition.Message))
+	}
+
+	return nil
+}
+
+func (ctrl *Controller) finish(key string, pending *pendingJob, err error) {
+	ctrl.mutex.Lock()
+	delete(ctrl.pending, key)
+	ctrl.mutex.Unlock()
+
+	pending.result <- err
+}
+
+func (ctrl *Controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.Errorf("Error computing workqueue key: %v", err)
+		return
+	}
+
+	ctrl.queue.Add(key)
+}
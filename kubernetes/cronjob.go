@@ -0,0 +1,158 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/wearemolecule/kube-scheduler/backoff"
+	"github.com/wearemolecule/kube-scheduler/scheduler"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// managedByLabel marks every CronJob this scheduler owns so SyncCronJobs can safely delete ones
+// that have fallen out of the desired jobList without touching unrelated CronJobs in the cluster.
+const managedByLabel = "kube-scheduler.molecule.io/managed-by"
+
+// SyncCronJobs reconciles the given jobList into batch/v1beta1.CronJob resources, creating,
+// updating, and deleting CronJobs so that the cluster matches the desired state exactly. This is
+// the entry point for scheduler.NativeCronJob mode, where Kubernetes itself (rather than an
+// in-process cron loop) is responsible for triggering runs, retries, concurrency, and history.
+func (c *client) SyncCronJobs(jobs map[string]scheduler.Job) error {
+	existing, err := c.listManagedCronJobs()
+	if err != nil {
+		return errors.Wrap(err, "Error listing existing cron jobs")
+	}
+
+	desired := make(map[string]bool, len(jobs))
+	for name, job := range jobs {
+		if job.Cron == "" {
+			continue
+		}
+		key := pendingKey(job.Namespace, name)
+		desired[key] = true
+
+		cronJob, err := c.buildCronJob(name, job)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Error building cron job %s", name))
+		}
+
+		if current, ok := existing[key]; ok {
+			cronJob.ObjectMeta.ResourceVersion = current.ObjectMeta.ResourceVersion
+			if err := c.updateCronJob(cronJob, job.Namespace, backoffFor(job)); err != nil {
+				return errors.Wrap(err, fmt.Sprintf("Error updating cron job %s", name))
+			}
+			continue
+		}
+
+		if err := c.createCronJob(cronJob, job.Namespace, backoffFor(job)); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Error creating cron job %s", name))
+		}
+	}
+
+	for key, cronJob := range existing {
+		if desired[key] {
+			continue
+		}
+
+		if err := c.deleteCronJob(cronJob, cronJob.ObjectMeta.Namespace, backoff.Default); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Error deleting cron job %s", cronJob.Name))
+		}
+	}
+
+	return nil
+}
+
+func (c *client) buildCronJob(name string, job scheduler.Job) (*batchv1beta1.CronJob, error) {
+	kubeJob, err := c.readJobTemplate(job)
+	if err != nil {
+		return nil, err
+	}
+
+	firstContainer := &kubeJob.Spec.Template.Spec.Containers[0]
+	firstContainer.Args = job.Args
+	if job.Image != "" {
+		firstContainer.Image = job.Image
+	}
+
+	concurrencyPolicy := batchv1beta1.AllowConcurrent
+	if job.ConcurrencyPolicy != "" {
+		concurrencyPolicy = batchv1beta1.ConcurrencyPolicy(job.ConcurrencyPolicy)
+	}
+
+	return &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: job.Namespace,
+			Labels:    map[string]string{managedByLabel: "kube-scheduler"},
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   job.Cron,
+			ConcurrencyPolicy:          concurrencyPolicy,
+			StartingDeadlineSeconds:    job.StartingDeadlineSeconds,
+			SuccessfulJobsHistoryLimit: job.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     job.FailedJobsHistoryLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: kubeJob.Spec,
+			},
+		},
+	}, nil
+}
+
+// listManagedCronJobs returns every CronJob this scheduler manages, keyed by namespace/name (via
+// pendingKey) rather than bare name - two namespaces can otherwise run a same-named job, and a
+// bare-name key would treat one as an update of the other and copy its ResourceVersion across
+// namespaces.
+func (c *client) listManagedCronJobs() (map[string]*batchv1beta1.CronJob, error) {
+	thing, err := autoRetry("list_cronjobs", backoff.Default, func() (interface{}, error) {
+		cronJobsClient := c.client.BatchV1beta1().CronJobs(metav1.NamespaceAll)
+		return cronJobsClient.List(context.Background(), metav1.ListOptions{
+			LabelSelector: labels.Set{managedByLabel: "kube-scheduler"}.AsSelector().String(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := thing.(*batchv1beta1.CronJobList)
+	byKey := make(map[string]*batchv1beta1.CronJob, len(list.Items))
+	for i := range list.Items {
+		byKey[pendingKey(list.Items[i].Namespace, list.Items[i].Name)] = &list.Items[i]
+	}
+
+	return byKey, nil
+}
+
+func (c *client) createCronJob(cronJob *batchv1beta1.CronJob, namespace string, policy backoff.Backoff) error {
+	glog.V(2).Infof("Creating kubernetes cron job %s", cronJob.Name)
+	_, err := autoRetry("create_cronjob", policy, func() (interface{}, error) {
+		cronJobsClient := c.client.BatchV1beta1().CronJobs(namespace)
+		return cronJobsClient.Create(context.Background(), cronJob, metav1.CreateOptions{})
+	})
+
+	return err
+}
+
+func (c *client) updateCronJob(cronJob *batchv1beta1.CronJob, namespace string, policy backoff.Backoff) error {
+	glog.V(2).Infof("Updating kubernetes cron job %s", cronJob.Name)
+	_, err := autoRetry("update_cronjob", policy, func() (interface{}, error) {
+		cronJobsClient := c.client.BatchV1beta1().CronJobs(namespace)
+		return cronJobsClient.Update(context.Background(), cronJob, metav1.UpdateOptions{})
+	})
+
+	return err
+}
+
+func (c *client) deleteCronJob(cronJob *batchv1beta1.CronJob, namespace string, policy backoff.Backoff) error {
+	glog.V(2).Infof("Deleting kubernetes cron job %s", cronJob.Name)
+	_, err := autoRetry("delete_cronjob", policy, func() (interface{}, error) {
+		cronJobsClient := c.client.BatchV1beta1().CronJobs(namespace)
+		err := cronJobsClient.Delete(context.Background(), cronJob.Name, metav1.DeleteOptions{})
+		return nil, err
+	})
+
+	return err
+}
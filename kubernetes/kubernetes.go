@@ -2,6 +2,7 @@
 package kubernetes
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,18 +10,21 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"github.com/wearemolecule/kube-scheduler/backoff"
+	"github.com/wearemolecule/kube-scheduler/metrics"
 	"github.com/wearemolecule/kube-scheduler/scheduler"
-	"k8s.io/client-go/1.4/kubernetes"
-	"k8s.io/client-go/1.4/pkg/api"
-	"k8s.io/client-go/1.4/pkg/apis/batch/v1"
-	"k8s.io/client-go/1.4/pkg/fields"
-	"k8s.io/client-go/1.4/pkg/watch"
-	"k8s.io/client-go/1.4/rest"
-	"k8s.io/client-go/1.4/tools/clientcmd"
+	"k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 type ClientInterface interface {
 	RunJob(string, scheduler.Job) error
+	SyncCronJobs(map[string]scheduler.Job) error
+	RunLeaderElection(cfg LeaderElectionConfig, onStartedLeading, onStoppedLeading func()) error
+	CanListJobs() error
 }
 
 func NewClient(kubeConfigPath, schedulerConfigPath string) (*client, error) {
@@ -45,72 +49,34 @@ func NewClient(kubeConfigPath, schedulerConfigPath string) (*client, error) {
 		return nil, errors.Wrap(err, "Unable to create kubernetes client from config")
 	}
 
-	return &client{kubeClient, schedulerConfigPath}, nil
+	c := &client{client: kubeClient, schedulerConfigPath: schedulerConfigPath}
+	c.controller = newController(c)
+	go c.controller.Run(2, make(chan struct{}))
+
+	return c, nil
 }
 
 type client struct {
 	client              *kubernetes.Clientset
 	schedulerConfigPath string
+	controller          *Controller
 }
 
-// RunJob will create a k8s/batch.v1.Job inside the kubernetes cluster given a job template file.
+// RunJob will create a k8s/batch.v1.Job inside the kubernetes cluster given a job template file
+// and block until the Controller's informer observes it reach a terminal condition.
 //
-// We have seen three operations fail due to timeout issues: create, delete, watch.
-// All of these operations will be retried 3 timtes automatically.
+// Job status is no longer read off of a per-call watch.Interface. A single shared informer keeps
+// an eventually-consistent view of every job this scheduler owns, so a dropped watch connection
+// only costs a re-list instead of causing RunJob to return nil as if the job had completed.
 func (c *client) RunJob(name string, job scheduler.Job) error {
-	data, err := ioutil.ReadFile(c.jobPath(job))
-	if err != nil {
-		return errors.Wrap(err, "Error reading job template")
-	}
-
-	kubeJob := v1.Job{}
-	if err = json.Unmarshal(data, &kubeJob); err != nil {
-		return errors.Wrap(err, "Error parsing task pod")
-	}
-
-	glog.V(2).Infof("For %s found args: %v", name, job.Args)
-	glog.V(2).Infof("For %s found namespace: %s", name, job.Namespace)
-	firstContainer := &kubeJob.Spec.Template.Spec.Containers[0]
-	firstContainer.Args = job.Args
-	if job.Image != "" {
-		firstContainer.Image = job.Image
-	}
-	kubeJob.ObjectMeta.Namespace = job.Namespace
-
-	clusterJob, err := c.createJob(kubeJob, job.Namespace)
-	if err != nil {
-		return errors.Wrap(err, "Error creating kubernetes job")
-	}
-	defer c.deleteJob(clusterJob, job.Namespace)
-
-	events, err := c.watchJob(clusterJob, job.Namespace)
-	if err != nil {
-		return errors.Wrap(err, "Error creating job watcher")
-	}
-	defer events.Stop()
-
-	for event := range events.ResultChan() {
-		job := event.Object.(*v1.Job)
-		if len(job.Status.Conditions) > 0 {
-			condition := job.Status.Conditions[0]
-			if condition.Type == v1.JobComplete {
-				return nil
-			}
-
-			if condition.Type == v1.JobFailed {
-				return fmt.Errorf(condition.Message)
-			}
-		}
-	}
-
-	return nil
+	return c.controller.SubmitJob(name, job)
 }
 
-func (c *client) createJob(job v1.Job, namespace string) (*v1.Job, error) {
+func (c *client) createJob(job v1.Job, namespace string, policy backoff.Backoff) (*v1.Job, error) {
 	glog.V(2).Infof("Created kubernetes job %s", job.Name)
-	thing, err := autoRetry(func() (interface{}, error) {
-		jobsClient := c.client.Batch().Jobs(namespace)
-		return jobsClient.Create(&job)
+	thing, err := autoRetry("create_job", policy, func() (interface{}, error) {
+		jobsClient := c.client.BatchV1().Jobs(namespace)
+		return jobsClient.Create(context.Background(), &job, metav1.CreateOptions{})
 	})
 
 	// Check if the conversion went ok (nil values would otherwise cause panic)
@@ -121,50 +87,53 @@ func (c *client) createJob(job v1.Job, namespace string) (*v1.Job, error) {
 	return nil, err
 }
 
-func (c *client) deleteJob(job *v1.Job, namespace string) error {
+func (c *client) deleteJob(job *v1.Job, namespace string, policy backoff.Backoff) error {
 	glog.V(2).Infof("Deleted kubernetes job %s", job.Name)
-	_, err := autoRetry(func() (interface{}, error) {
-		jobsClient := c.client.Batch().Jobs(namespace)
-		err := jobsClient.Delete(job.Name, &api.DeleteOptions{})
+	_, err := autoRetry("delete_job", policy, func() (interface{}, error) {
+		jobsClient := c.client.BatchV1().Jobs(namespace)
+		err := jobsClient.Delete(context.Background(), job.Name, metav1.DeleteOptions{})
 		return nil, err
 	})
 
 	return err
 }
 
-func (c *client) watchJob(job *v1.Job, namespace string) (watch.Interface, error) {
-	glog.V(2).Infof("Watching kubernetes job %s for status events", job.Name)
-	thing, err := autoRetry(func() (interface{}, error) {
-		jobsClient := c.client.Batch().Jobs(namespace)
-		return jobsClient.Watch(api.ListOptions{
-			FieldSelector:   fields.OneTermEqualSelector("metadata.name", job.Name),
-			Watch:           true,
-			ResourceVersion: job.ResourceVersion,
-		})
-	})
-
-	return thing.(watch.Interface), err
+// CanListJobs performs a single, un-retried List call against batch/v1.Jobs. It backs the
+// /readyz endpoint, which should fail fast rather than retry - a slow cluster should be reported
+// as not-ready immediately, not after autoRetry's several seconds of backoff.
+func (c *client) CanListJobs() error {
+	_, err := c.client.BatchV1().Jobs(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	return err
 }
 
-func autoRetry(fn func() (interface{}, error)) (interface{}, error) {
-	var attempts int
-	var err error
-	var thing interface{}
-
-	for attempts < 3 {
-		thing, err = fn()
-		if err == nil {
-			return thing, nil
-		}
-
-		attempts += 1
-		time.Sleep(1 * time.Second)
-	}
-
-	return nil, err
+// autoRetry calls fn under policy, retrying only errors isRetriableKubeError accepts, and records
+// the total time spent (including retries) against kube_api_request_duration_seconds under op.
+func autoRetry(op string, policy backoff.Backoff, fn func() (interface{}, error)) (interface{}, error) {
+	start := time.Now()
+	defer func() {
+		metrics.KubeAPIRequestDurationSeconds.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	}()
 
+	return policy.Retry(fn, isRetriableKubeError)
 }
 
 func (c *client) jobPath(job scheduler.Job) string {
 	return fmt.Sprintf("%s/%s", c.schedulerConfigPath, job.Template)
 }
+
+// readJobTemplate loads and parses the k8s/batch.v1.Job template backing the given scheduler.Job.
+// It is shared by RunJob and SyncCronJobs since a CronJob's JobTemplateSpec is built from the same
+// on-disk template as a one-off Job.
+func (c *client) readJobTemplate(job scheduler.Job) (v1.Job, error) {
+	data, err := ioutil.ReadFile(c.jobPath(job))
+	if err != nil {
+		return v1.Job{}, errors.Wrap(err, "Error reading job template")
+	}
+
+	kubeJob := v1.Job{}
+	if err = json.Unmarshal(data, &kubeJob); err != nil {
+		return v1.Job{}, errors.Wrap(err, "Error parsing task pod")
+	}
+
+	return kubeJob, nil
+}
@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaderElectionConfig configures RunLeaderElection. Only one replica holding the Lease named
+// ResourceName in Namespace is ever the leader at a time.
+type LeaderElectionConfig struct {
+	Namespace     string
+	ResourceName  string
+	Identity      string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// RunLeaderElection blocks running leader election against a Lease object used as the lock.
+// onStartedLeading is called once this process acquires leadership; onStoppedLeading is called
+// the moment it loses leadership (including on a clean renew failure), so the caller can stop
+// doing leader-only work and exit, letting the Deployment restart it as a follower.
+func (c *client) RunLeaderElection(cfg LeaderElectionConfig, onStartedLeading, onStoppedLeading func()) error {
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		cfg.Namespace,
+		cfg.ResourceName,
+		c.client.CoreV1(),
+		c.client.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: cfg.Identity},
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create leader election lock")
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: cfg.LeaseDuration,
+		RenewDeadline: cfg.RenewDeadline,
+		RetryPeriod:   cfg.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				glog.Infof("%s acquired the %s leader election lease", cfg.Identity, cfg.ResourceName)
+				onStartedLeading()
+			},
+			OnStoppedLeading: func() {
+				glog.Infof("%s lost the %s leader election lease", cfg.Identity, cfg.ResourceName)
+				onStoppedLeading()
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Unable to create leader elector")
+	}
+
+	elector.Run(context.Background())
+	return nil
+}
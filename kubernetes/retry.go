@@ -0,0 +1,40 @@
+package kubernetes
+
+import (
+	"net"
+	"strings"
+
+	"github.com/wearemolecule/kube-scheduler/backoff"
+	"github.com/wearemolecule/kube-scheduler/scheduler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// backoffFor builds the retry policy autoRetry should use while running job, preferring its
+// Retries/Backoff fields and falling back to backoff.Default for anything unset.
+func backoffFor(job scheduler.Job) backoff.Backoff {
+	b := job.Backoff.Backoff()
+	if job.Retries != nil {
+		b.Steps = *job.Retries
+	}
+
+	return b
+}
+
+// isRetriableKubeError reports whether err is worth a retry: a network-level failure, a transient
+// server condition (timeout, too many requests, internal error), or a connection reset - not a
+// validation error that will fail identically on every attempt.
+func isRetriableKubeError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+
+	if strings.Contains(err.Error(), "connection reset") {
+		return true
+	}
+
+	return apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) || apierrors.IsInternalError(err)
+}
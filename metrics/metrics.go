@@ -0,0 +1,58 @@
+// Package metrics holds the Prometheus collectors kube-scheduler exposes on /metrics. Collectors
+// live here, rather than in the packages that update them, so scheduler, kubernetes, and main can
+// all reach the same registry without importing one another.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// JobRunsTotal counts every scheduled job run, labeled by its outcome.
+	JobRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduled_job_runs_total",
+		Help: "Total number of scheduled job runs, labeled by result (success or failure).",
+	}, []string{"job", "namespace", "result"})
+
+	// JobDurationSeconds tracks how long a scheduled job took to reach a terminal condition.
+	// Scheduled jobs routinely run minutes to hours, so this uses explicit buckets spanning that
+	// range instead of prometheus.DefBuckets (which tops out at 10s and would put nearly every
+	// observation in +Inf).
+	JobDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scheduled_job_duration_seconds",
+		Help:    "Duration of scheduled job runs in seconds.",
+		Buckets: []float64{1, 5, 15, 30, 60, 300, 900, 1800, 3600, 7200, 14400, 28800},
+	}, []string{"job", "namespace"})
+
+	// JobsInFlight reports how many scheduled jobs are currently running. It is a gauge driven by
+	// StartJob/FinishJob rather than a point-in-time count, since the scheduler never lists running
+	// jobs itself.
+	JobsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "scheduled_job_in_flight",
+		Help: "Number of scheduled jobs currently running.",
+	}, []string{"job", "namespace"})
+
+	// CronMissedTicksTotal counts cron ticks that were skipped because the previous run of that
+	// job was still in flight when the next tick arrived.
+	CronMissedTicksTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "cron_missed_ticks_total",
+		Help: "Total number of cron ticks skipped because the previous run of that job was still running.",
+	})
+
+	// KubeAPIRequestDurationSeconds times the kubernetes API calls made through autoRetry,
+	// including any retries, labeled by operation. Buckets span milliseconds to tens of seconds,
+	// consistent with autoRetry's default policy of a few retries at roughly a second apart.
+	KubeAPIRequestDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kube_api_request_duration_seconds",
+		Help:    "Duration of kubernetes API requests made through autoRetry, labeled by operation.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"op"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		JobRunsTotal,
+		JobDurationSeconds,
+		JobsInFlight,
+		CronMissedTicksTotal,
+		KubeAPIRequestDurationSeconds,
+	)
+}
@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// pagerDutyBackend pages via the PagerDuty Events API v2. It only ever triggers - resolving is
+// left to the on-call responder, since a scheduled job re-running is a new trigger rather than the
+// same incident recovering on its own.
+type pagerDutyBackend struct {
+	name       string
+	routingKey string
+}
+
+func newPagerDutyBackend(name string, settings map[string]string) (*pagerDutyBackend, error) {
+	routingKey := settings["routing_key"]
+	if routingKey == "" {
+		return nil, errors.New("pagerduty backend requires a routing_key setting")
+	}
+
+	return &pagerDutyBackend{name: name, routingKey: routingKey}, nil
+}
+
+func (b *pagerDutyBackend) Name() string {
+	return b.name
+}
+
+func (b *pagerDutyBackend) Notify(msg string, err error) error {
+	return b.trigger(msg, errString(err))
+}
+
+// NotifySuccess is a no-op - PagerDuty backends only page on failure.
+func (b *pagerDutyBackend) NotifySuccess(msg string, duration time.Duration) error {
+	return nil
+}
+
+func (b *pagerDutyBackend) trigger(summary, details string) error {
+	event := map[string]interface{}{
+		"routing_key":  b.routingKey,
+		"event_action": "trigger",
+		"payload": map[string]string{
+			"summary":  summary,
+			"source":   "kube-scheduler",
+			"severity": "critical",
+			"details":  details,
+		},
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(pagerDutyEventsURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "Unable to POST to PagerDuty")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("PagerDuty events API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
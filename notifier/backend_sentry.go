@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"os"
+	"time"
+
+	"github.com/getsentry/raven-go"
+	"github.com/pkg/errors"
+	"github.com/wearemolecule/kube-scheduler/backoff"
+)
+
+// sentryBackend preserves the original behavior: up to 3 attempts, 1s apart, only active when
+// SENTRY_DSN is set. It has nothing useful to say about a successful run, so NotifySuccess is a
+// no-op.
+//
+// TODO: Evaluate if we still need retry logic for Sentry
+type sentryBackend struct {
+	usingSentry bool
+}
+
+func newSentryBackend(namespace string) *sentryBackend {
+	var usingSentry bool
+	if os.Getenv("SENTRY_DSN") != "" {
+		raven.SetEnvironment(namespace)
+		usingSentry = true
+	}
+
+	return &sentryBackend{usingSentry}
+}
+
+func (b *sentryBackend) Name() string {
+	return "sentry"
+}
+
+func (b *sentryBackend) Notify(msg string, err error) error {
+	if !b.usingSentry {
+		return nil
+	}
+
+	// Sentry's only failure mode here is "the POST didn't go through" - always worth a retry,
+	// unlike the kubernetes client's isRetriableKubeError which excludes validation errors.
+	_, retryErr := backoff.Default.Retry(func() (interface{}, error) {
+		return nil, b.capture(msg, err)
+	}, func(error) bool { return true })
+	if retryErr != nil {
+		return errors.Wrap(retryErr, "Unable to POST to Sentry")
+	}
+
+	return nil
+}
+
+func (b *sentryBackend) NotifySuccess(msg string, duration time.Duration) error {
+	return nil
+}
+
+func (b *sentryBackend) capture(msg string, err error) error {
+	msgID := raven.CaptureErrorAndWait(err, map[string]string{"message": msg})
+	if msgID == "" {
+		return errors.New("Posting to Sentry failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// slackBackend posts messages to a Slack incoming webhook.
+type slackBackend struct {
+	name       string
+	webhookURL string
+}
+
+func newSlackBackend(name string, settings map[string]string) (*slackBackend, error) {
+	webhookURL := settings["webhook_url"]
+	if webhookURL == "" {
+		return nil, errors.New("slack backend requires a webhook_url setting")
+	}
+
+	return &slackBackend{name: name, webhookURL: webhookURL}, nil
+}
+
+func (b *slackBackend) Name() string {
+	return b.name
+}
+
+func (b *slackBackend) Notify(msg string, err error) error {
+	return b.post(fmt.Sprintf(":rotating_light: %s: %v", msg, err))
+}
+
+func (b *slackBackend) NotifySuccess(msg string, duration time.Duration) error {
+	return b.post(fmt.Sprintf(":white_check_mark: %s (%s)", msg, duration))
+}
+
+func (b *slackBackend) post(text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(b.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "Unable to POST to Slack")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("Slack webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// stderrBackend logs every event as a structured JSON line via glog, so even a scheduler with no
+// external notifiers configured has a machine-parseable record of every job run.
+type stderrBackend struct{}
+
+func newStderrBackend() *stderrBackend {
+	return &stderrBackend{}
+}
+
+func (b *stderrBackend) Name() string {
+	return "stderr"
+}
+
+func (b *stderrBackend) Notify(msg string, err error) error {
+	return b.log(map[string]interface{}{
+		"message": msg,
+		"error":   errString(err),
+		"result":  "failure",
+	})
+}
+
+func (b *stderrBackend) NotifySuccess(msg string, duration time.Duration) error {
+	return b.log(map[string]interface{}{
+		"message":     msg,
+		"result":      "success",
+		"duration_ms": int64(duration / time.Millisecond),
+	})
+}
+
+func (b *stderrBackend) log(event map[string]interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	glog.Info(string(data))
+	return nil
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// webhookBackend POSTs a JSON event payload to an arbitrary URL, for destinations that don't
+// warrant a purpose-built backend.
+type webhookBackend struct {
+	name string
+	url  string
+}
+
+func newWebhookBackend(name string, settings map[string]string) (*webhookBackend, error) {
+	url := settings["url"]
+	if url == "" {
+		return nil, errors.New("webhook backend requires a url setting")
+	}
+
+	return &webhookBackend{name: name, url: url}, nil
+}
+
+func (b *webhookBackend) Name() string {
+	return b.name
+}
+
+func (b *webhookBackend) Notify(msg string, err error) error {
+	return b.post(map[string]interface{}{
+		"message": msg,
+		"error":   errString(err),
+		"result":  "failure",
+	})
+}
+
+func (b *webhookBackend) NotifySuccess(msg string, duration time.Duration) error {
+	return b.post(map[string]interface{}{
+		"message":     msg,
+		"result":      "success",
+		"duration_ms": int64(duration / time.Millisecond),
+	})
+}
+
+func (b *webhookBackend) post(event map[string]interface{}) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(b.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "Unable to POST to webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("Webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
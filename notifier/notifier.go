@@ -1,63 +1,106 @@
-// The notifier package provides a way to communicate errors to external services.  The only supported service is Sentry.
+// The notifier package fans job failure and success events out to one or more Backend
+// implementations: Sentry, Slack, PagerDuty, a generic webhook, and stderr/structured logs.
 package notifier
 
 import (
-	"os"
+	"fmt"
 	"time"
 
-	"github.com/getsentry/raven-go"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 )
 
 type ClientInterface interface {
-	Notify(string, error) error
+	// Notify reports an error. names optionally selects additional configured backends beyond the
+	// defaults (Sentry + stderr) every message always reaches - typically a job's `notify:` list.
+	Notify(msg string, err error, names ...string) error
+	// NotifySuccess reports a successful run and how long it took, so the same subsystem doubles
+	// as an observability hook rather than only surfacing failures.
+	NotifySuccess(msg string, duration time.Duration, names ...string) error
 }
 
-func NewClient(namespace string) *client {
-	var usingSentry bool
-	if os.Getenv("SENTRY_DSN") != "" {
-		raven.SetEnvironment(namespace)
-		usingSentry = true
+// Backend is a single destination a notifier.client fans events out to.
+type Backend interface {
+	Name() string
+	Notify(msg string, err error) error
+	NotifySuccess(msg string, duration time.Duration) error
+}
+
+// BackendConfig describes one entry of the schedule's `notifiers:` block. Type selects which
+// Backend implementation to build; Settings carries backend-specific fields (webhook URLs,
+// routing keys, etc.) so this package stays the only one that knows about concrete backends.
+type BackendConfig struct {
+	Type     string            `yaml:"type"`
+	Settings map[string]string `yaml:"settings"`
+}
+
+func NewClient(namespace string, configs map[string]BackendConfig) *client {
+	named := make(map[string]Backend, len(configs))
+	for name, cfg := range configs {
+		backend, err := newBackend(name, cfg)
+		if err != nil {
+			glog.Error(errors.Wrap(err, fmt.Sprintf("Unable to configure notifier backend %s", name)))
+			continue
+		}
+
+		named[name] = backend
 	}
 
-	return &client{usingSentry}
+	return &client{
+		defaultBackends: []Backend{newStderrBackend(), newSentryBackend(namespace)},
+		namedBackends:   named,
+	}
 }
 
 type client struct {
-	usingSentry bool
+	defaultBackends []Backend
+	namedBackends   map[string]Backend
+}
+
+func (c *client) Notify(msg string, err error, names ...string) error {
+	return c.dispatch(names, func(b Backend) error { return b.Notify(msg, err) })
 }
 
-// Notify will log to stdout and post error and message to Sentry.
-//
-// TODO: Evaluate if we still need retry logic for Sentry
-func (c *client) Notify(msg string, err error) error {
-	glog.Info(errors.Wrap(err, msg))
+func (c *client) NotifySuccess(msg string, duration time.Duration, names ...string) error {
+	return c.dispatch(names, func(b Backend) error { return b.NotifySuccess(msg, duration) })
+}
 
-	var nErr error
-	var attempts int
-	for attempts < 3 {
-		if nErr = c.notifySentry(msg, err); nErr == nil {
-			return nil
+// dispatch runs fn against every default backend plus any backend named in names, logging (but
+// not aborting on) individual backend failures, and returns the first error encountered if any.
+func (c *client) dispatch(names []string, fn func(Backend) error) error {
+	backends := append([]Backend{}, c.defaultBackends...)
+	for _, name := range names {
+		backend, ok := c.namedBackends[name]
+		if !ok {
+			glog.Warningf("Unknown notifier backend %s", name)
+			continue
 		}
 
-		attempts += 1
-		time.Sleep(1 * time.Second)
+		backends = append(backends, backend)
 	}
 
-	glog.Info(errors.Wrap(nErr, "Unable to POST to Sentry"))
-	return nErr
-}
-
-func (c *client) notifySentry(msg string, err error) error {
-	if !c.usingSentry {
-		return nil
+	var firstErr error
+	for _, backend := range backends {
+		if err := fn(backend); err != nil {
+			glog.Error(errors.Wrap(err, fmt.Sprintf("Notifier backend %s failed", backend.Name())))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
 	}
 
-	msgID := raven.CaptureErrorAndWait(err, map[string]string{"message": msg})
-	if msgID == "" {
-		return errors.New("Posting to Sentry failed")
-	}
+	return firstErr
+}
 
-	return nil
+func newBackend(name string, cfg BackendConfig) (Backend, error) {
+	switch cfg.Type {
+	case "slack":
+		return newSlackBackend(name, cfg.Settings)
+	case "pagerduty":
+		return newPagerDutyBackend(name, cfg.Settings)
+	case "webhook":
+		return newWebhookBackend(name, cfg.Settings)
+	default:
+		return nil, errors.Errorf("Unknown notifier backend type %q", cfg.Type)
+	}
 }
@@ -0,0 +1,21 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SchemeBuilder and AddToScheme follow the same registration pattern as the built-in kubernetes
+// API groups so ScheduledJob can be decoded through the same scheme used elsewhere. CRDSource
+// calls AddToScheme against the client-go scheme before building its REST client.
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&ScheduledJob{},
+		&ScheduledJobList{},
+	)
+	return nil
+}
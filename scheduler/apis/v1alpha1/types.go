@@ -0,0 +1,86 @@
+// Package v1alpha1 contains the API types for the scheduler.molecule.io ScheduledJob custom
+// resource. A ScheduledJob carries the same fields as one entry of schedule.yml, but can be
+// kubectl apply'd and watched as a first-class kubernetes object instead of baked into a
+// ConfigMap.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/wearemolecule/kube-scheduler/backoff"
+)
+
+const (
+	GroupName = "scheduler.molecule.io"
+	Version   = "v1alpha1"
+)
+
+// SchemeGroupVersion is the group version used to register ScheduledJob and ScheduledJobList.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: Version}
+
+// ScheduledJob is the CRD representation of a scheduler.Job.
+type ScheduledJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScheduledJobSpec `json:"spec"`
+}
+
+type ScheduledJobSpec struct {
+	Cron        string `json:"cron"`
+	Template    string `json:"template"`
+	Description string `json:"description,omitempty"`
+	// Allows overriding the image given in the job spec.
+	Image     string   `json:"image,omitempty"`
+	Args      []string `json:"args,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+
+	// Notify names notifier backends that should additionally hear about this job's failures and
+	// successes, beyond the scheduler's default backends.
+	Notify []string `json:"notify,omitempty"`
+
+	// The following fields are only consumed in scheduler.NativeCronJob mode, where they are mapped
+	// directly onto the equivalent batch/v1beta1.CronJobSpec fields.
+	ConcurrencyPolicy          string `json:"concurrencyPolicy,omitempty"`
+	StartingDeadlineSeconds    *int64 `json:"startingDeadlineSeconds,omitempty"`
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	FailedJobsHistoryLimit     *int32 `json:"failedJobsHistoryLimit,omitempty"`
+
+	// Retries and Backoff override how the kubernetes client retries its own API calls (create,
+	// delete, etc.) while running this job. See scheduler.Job for the equivalent schedule.yml
+	// fields.
+	Retries *int            `json:"retries,omitempty"`
+	Backoff *backoff.Config `json:"backoff,omitempty"`
+}
+
+// ScheduledJobList is a list of ScheduledJob, required for client-go's List and Watch codecs.
+type ScheduledJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ScheduledJob `json:"items"`
+}
+
+func (in *ScheduledJob) GetObjectKind() schema.ObjectKind     { return &in.TypeMeta }
+func (in *ScheduledJobList) GetObjectKind() schema.ObjectKind { return &in.TypeMeta }
+
+// DeepCopyObject satisfies runtime.Object. Hand-written since this project does not run
+// deepcopy-gen.
+func (in *ScheduledJob) DeepCopyObject() runtime.Object {
+	out := *in
+	out.Spec.Args = append([]string(nil), in.Spec.Args...)
+	return &out
+}
+
+// DeepCopyObject satisfies runtime.Object. Hand-written since this project does not run
+// deepcopy-gen.
+func (in *ScheduledJobList) DeepCopyObject() runtime.Object {
+	out := *in
+	out.Items = make([]ScheduledJob, len(in.Items))
+	for i := range in.Items {
+		out.Items[i] = *in.Items[i].DeepCopyObject().(*ScheduledJob)
+	}
+	return &out
+}
@@ -0,0 +1,208 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	schedulerv1alpha1 "github.com/wearemolecule/kube-scheduler/scheduler/apis/v1alpha1"
+)
+
+const scheduledJobPlural = "scheduledjobs"
+
+// CRDSource loads and watches ScheduledJob custom resources in a single namespace. On first use
+// it registers the scheduledjobs.scheduler.molecule.io CRD if it does not already exist, so an
+// operator only has to `kubectl apply` a ScheduledJob for a running scheduler to pick it up - no
+// ConfigMap bake-in or redeploy required.
+type CRDSource struct {
+	Namespace      string
+	KubeConfigPath string
+
+	restClient *rest.RESTClient
+}
+
+func (s *CRDSource) Load() (jobList, error) {
+	if err := s.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	list := &schedulerv1alpha1.ScheduledJobList{}
+	err := s.restClient.Get().
+		Namespace(s.Namespace).
+		Resource(scheduledJobPlural).
+		Do(context.Background()).
+		Into(list)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list ScheduledJob resources")
+	}
+
+	return jobListFromScheduledJobs(list.Items), nil
+}
+
+// Watch starts an informer over ScheduledJob resources in Namespace and calls onChange with the
+// full, current job list on every add, update, or delete. Once the client is built it blocks for
+// the lifetime of the process: the informer retries list and watch errors itself and never
+// returns them, so Watch only returns an error from ensureClient.
+func (s *CRDSource) Watch(onChange func(jobList)) error {
+	if err := s.ensureClient(); err != nil {
+		return err
+	}
+
+	var store cache.Store
+	store, controller := cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				list := &schedulerv1alpha1.ScheduledJobList{}
+				err := s.restClient.Get().
+					Namespace(s.Namespace).
+					Resource(scheduledJobPlural).
+					VersionedParams(&options, scheme.ParameterCodec).
+					Do(context.Background()).
+					Into(list)
+				return list, err
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.Watch = true
+				return s.restClient.Get().
+					Namespace(s.Namespace).
+					Resource(scheduledJobPlural).
+					VersionedParams(&options, scheme.ParameterCodec).
+					Watch(context.Background())
+			},
+		},
+		&schedulerv1alpha1.ScheduledJob{},
+		30*time.Second,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { onChange(jobListFromStore(store)) },
+			UpdateFunc: func(old, new interface{}) { onChange(jobListFromStore(store)) },
+			DeleteFunc: func(obj interface{}) { onChange(jobListFromStore(store)) },
+		},
+	)
+
+	stopCh := make(chan struct{})
+	controller.Run(stopCh)
+
+	return nil
+}
+
+func jobListFromStore(store cache.Store) jobList {
+	jobs := make(jobList, len(store.List()))
+	for _, obj := range store.List() {
+		sj := obj.(*schedulerv1alpha1.ScheduledJob)
+		jobs[sj.Name] = jobFromScheduledJobSpec(sj.Spec)
+	}
+
+	return jobs
+}
+
+func jobListFromScheduledJobs(items []schedulerv1alpha1.ScheduledJob) jobList {
+	jobs := make(jobList, len(items))
+	for _, item := range items {
+		jobs[item.Name] = jobFromScheduledJobSpec(item.Spec)
+	}
+
+	return jobs
+}
+
+func jobFromScheduledJobSpec(spec schedulerv1alpha1.ScheduledJobSpec) Job {
+	return Job{
+		Cron:                       spec.Cron,
+		Template:                   spec.Template,
+		Description:                spec.Description,
+		Image:                      spec.Image,
+		Args:                       spec.Args,
+		Namespace:                  spec.Namespace,
+		Notify:                     spec.Notify,
+		ConcurrencyPolicy:          spec.ConcurrencyPolicy,
+		StartingDeadlineSeconds:    spec.StartingDeadlineSeconds,
+		SuccessfulJobsHistoryLimit: spec.SuccessfulJobsHistoryLimit,
+		FailedJobsHistoryLimit:     spec.FailedJobsHistoryLimit,
+		Retries:                    spec.Retries,
+		Backoff:                    spec.Backoff,
+	}
+}
+
+// ensureClient builds the REST client used to talk to the ScheduledJob CRD, registering the CRD
+// itself (if needed) and the Go types with the client-go scheme (so Codecs knows how to decode
+// them - AddToScheme is otherwise never called, and every Into(list) would fail at runtime with
+// "no kind is registered for the type v1alpha1.ScheduledJob").
+func (s *CRDSource) ensureClient() error {
+	if s.restClient != nil {
+		return nil
+	}
+
+	var (
+		kubeConfig *rest.Config
+		err        error
+	)
+
+	if s.KubeConfigPath == "" {
+		kubeConfig, err = rest.InClusterConfig()
+	} else {
+		kubeConfig, err = clientcmd.BuildConfigFromFlags("", s.KubeConfigPath)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to connect to kubernetes")
+	}
+
+	if err := registerScheduledJobCRD(kubeConfig); err != nil {
+		return errors.Wrap(err, "Failed to register ScheduledJob CRD")
+	}
+
+	if err := schedulerv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		return errors.Wrap(err, "Failed to register ScheduledJob types")
+	}
+
+	crdConfig := *kubeConfig
+	crdConfig.GroupVersion = &schedulerv1alpha1.SchemeGroupVersion
+	crdConfig.APIPath = "/apis"
+	crdConfig.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	restClient, err := rest.RESTClientFor(&crdConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to build ScheduledJob REST client")
+	}
+
+	s.restClient = restClient
+	return nil
+}
+
+func registerScheduledJobCRD(kubeConfig *rest.Config) error {
+	clientset, err := apiextensionsclientset.NewForConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	crd := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: scheduledJobPlural + "." + schedulerv1alpha1.GroupName,
+		},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group:   schedulerv1alpha1.GroupName,
+			Version: schedulerv1alpha1.Version,
+			Scope:   apiextensionsv1beta1.NamespaceScoped,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Plural: scheduledJobPlural,
+				Kind:   "ScheduledJob",
+			},
+		},
+	}
+
+	_, err = clientset.ApiextensionsV1beta1().CustomResourceDefinitions().Create(context.Background(), crd, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
@@ -3,47 +3,95 @@ package scheduler
 
 import (
 	"fmt"
-	"io/ioutil"
 	"sync"
+	"time"
 
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"github.com/robfig/cron"
-	"gopkg.in/yaml.v2"
+	"github.com/wearemolecule/kube-scheduler/backoff"
+	"github.com/wearemolecule/kube-scheduler/metrics"
 )
 
 type ClientInterface interface {
 	AsyncAddScheduledJob(Job, func())
 	JobList() jobList
+	Mode() Mode
 	Start()
 	Stop()
+	Reset()
+	Changes() <-chan jobList
 	Running(string, Job) bool
 	StartJob(string, Job)
-	FinishJob(string, Job)
+	FinishJob(string, Job, error)
+	LastTick() time.Time
+	Drain()
 }
 
-func NewClient(scheduleConfigPath, scheduleConfigName string) (*client, error) {
-	data, err := ioutil.ReadFile(fullPath(scheduleConfigPath, scheduleConfigName))
-	if err != nil {
-		return nil, errors.Wrap(err, "Unable to open schedule config file")
-	}
+// Mode controls how the scheduler drives job execution.
+type Mode int
 
-	var jobList jobList
-	if err := yaml.Unmarshal(data, &jobList); err != nil {
-		return nil, errors.Wrap(err, "Unable to unmarshal schedule yaml")
+const (
+	// InProcess runs an in-process cron loop that calls kubernetes.ClientInterface.RunJob directly.
+	InProcess Mode = iota
+	// NativeCronJob hands scheduling off to Kubernetes by reconciling jobList into batch/v1beta1.CronJob
+	// resources and never starts the in-process cron loop.
+	NativeCronJob
+)
+
+// NewClient loads the initial jobList from source and prepares a client to run it on the given
+// Mode. If source also implements Watcher, Start will additionally watch it for live changes -
+// see Changes.
+func NewClient(source Source, mode Mode) (*client, error) {
+	jobs, err := source.Load()
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to load job list")
 	}
 
-	return &client{jobList: jobList, jobLock: make(map[string]string), mutex: &sync.Mutex{}, cronRunner: cron.New()}, nil
+	return &client{
+		jobList:    jobs,
+		source:     source,
+		jobLock:    make(map[string]time.Time),
+		mutex:      &sync.Mutex{},
+		cronRunner: cron.New(),
+		mode:       mode,
+		changes:    make(chan jobList, 1),
+		lastTick:   time.Now(),
+	}, nil
 }
 
 // AsyncAddScheduledJob will schedule the run of the given function using the jobs cron field.
 // Must call client.Start() before jobs are run.
 func (c *client) AsyncAddScheduledJob(job Job, fn func()) {
-	c.cronRunner.AddFunc(job.Cron, fn)
+	c.cronRunner.AddFunc(job.Cron, func() {
+		c.mutex.Lock()
+		c.lastTick = time.Now()
+		c.mutex.Unlock()
+
+		fn()
+	})
 }
 
 // Start will begin the process of running the scheduled jobs.
 // Must call client.Stop() once the process is finished.
+//
+// If the client's Source also implements Watcher, Start additionally begins watching it exactly
+// once for the lifetime of the client; every change is published on Changes so the caller can
+// Reset, re-register AsyncAddScheduledJob calls against the new JobList, and Start again.
 func (c *client) Start() {
+	c.watchOnce.Do(func() {
+		watcher, ok := c.source.(Watcher)
+		if !ok {
+			return
+		}
+
+		go func() {
+			if err := watcher.Watch(c.onSourceChange); err != nil {
+				glog.Error(errors.Wrap(err, "Error watching schedule source"))
+			}
+		}()
+	})
+
 	c.cronRunner.Start()
 }
 
@@ -51,6 +99,27 @@ func (c *client) Stop() {
 	c.cronRunner.Stop()
 }
 
+// Reset stops the current cron runner and replaces it with a fresh one so AsyncAddScheduledJob
+// can be called again without duplicating entries carried over from before a Changes event.
+func (c *client) Reset() {
+	c.cronRunner.Stop()
+	c.cronRunner = cron.New()
+}
+
+// Changes publishes the full, current JobList every time the client's Source reports the
+// schedule has changed. Only meaningful for Sources that implement Watcher, such as CRDSource.
+func (c *client) Changes() <-chan jobList {
+	return c.changes
+}
+
+func (c *client) onSourceChange(jobs jobList) {
+	glog.Info("Schedule source reported a change, publishing updated job list")
+	c.mutex.Lock()
+	c.jobList = jobs
+	c.mutex.Unlock()
+	c.changes <- jobs
+}
+
 // Running will determine if the given job is already running, scoped to namespace, and is thread-safe.
 // Must call client.StartJob() to add it to the running queue.
 // Must call client.FinishJob() to remove it from the running queue.
@@ -65,20 +134,60 @@ func (c *client) Running(name string, job Job) bool {
 func (c *client) StartJob(name string, job Job) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.jobLock[jobKey(name, job)] = "running"
+	c.jobLock[jobKey(name, job)] = time.Now()
+	metrics.JobsInFlight.WithLabelValues(name, job.Namespace).Inc()
 }
 
-// FinishJob will remove the job from the running queue and is thread-safe.
-func (c *client) FinishJob(name string, job Job) {
+// FinishJob will remove the job from the running queue and is thread-safe. err is the result of
+// the run, used only to label scheduled_job_runs_total - pass the same error RunJob returned, or
+// nil on success.
+func (c *client) FinishJob(name string, job Job, err error) {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	start, ok := c.jobLock[jobKey(name, job)]
 	delete(c.jobLock, jobKey(name, job))
+	c.mutex.Unlock()
+
+	metrics.JobsInFlight.WithLabelValues(name, job.Namespace).Dec()
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	metrics.JobRunsTotal.WithLabelValues(name, job.Namespace, result).Inc()
+
+	if ok {
+		metrics.JobDurationSeconds.WithLabelValues(name, job.Namespace).Observe(time.Since(start).Seconds())
+	}
+}
+
+// LastTick returns the time the cron runner last fired any scheduled job, used to drive the
+// liveness check in InProcess mode. It reflects client creation time until the first tick.
+func (c *client) LastTick() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lastTick
+}
+
+// Drain clears the in-memory record of running jobs. It is called when this replica loses
+// leadership so it doesn't exit holding stale "running" state - the jobs it started keep running
+// in the cluster, but the promoted follower starts with a clean jobLock.
+func (c *client) Drain() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.jobLock = make(map[string]time.Time)
 }
 
 func (c *client) JobList() jobList {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	return c.jobList
 }
 
+// Mode returns the scheduling mode the client was created with.
+func (c *client) Mode() Mode {
+	return c.mode
+}
+
 type Job struct {
 	Cron        string `yaml:"cron"`
 	Template    string `yaml:"template"`
@@ -87,13 +196,38 @@ type Job struct {
 	Image     string   `yaml:"image"`
 	Args      []string `yaml:"args"`
 	Namespace string   `yaml:"namespace"`
+
+	// Notify names entries in the notifiers: block that, in addition to the default notifier
+	// backends, should hear about this job's failures and successes - e.g. `notify: [pagerduty-critical]`.
+	Notify []string `yaml:"notify"`
+
+	// The following fields are only consumed in scheduler.NativeCronJob mode, where they are mapped
+	// directly onto the equivalent batch/v1beta1.CronJobSpec fields.
+	ConcurrencyPolicy          string `yaml:"concurrency_policy"`
+	StartingDeadlineSeconds    *int64 `yaml:"starting_deadline_seconds"`
+	SuccessfulJobsHistoryLimit *int32 `yaml:"successful_jobs_history_limit"`
+	FailedJobsHistoryLimit     *int32 `yaml:"failed_jobs_history_limit"`
+
+	// Retries overrides the maximum number of attempts, including the first, that the kubernetes
+	// client makes for an API call (create, delete, etc.) while running this job - not how many
+	// times the job's pod itself retries. It replaces backoff.Steps, so Retries: 1 means a single
+	// attempt with no retry. Defaults to backoff.Default.Steps.
+	Retries *int `yaml:"retries"`
+	// Backoff overrides the delay between those retries. Any field left zero falls back to
+	// backoff.Default's corresponding field.
+	Backoff *backoff.Config `yaml:"backoff"`
 }
 
 type client struct {
 	jobList    jobList
-	jobLock    map[string]string
+	source     Source
+	jobLock    map[string]time.Time
 	mutex      *sync.Mutex
 	cronRunner *cron.Cron
+	mode       Mode
+	changes    chan jobList
+	watchOnce  sync.Once
+	lastTick   time.Time
 }
 
 type jobList map[string]Job
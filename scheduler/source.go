@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/wearemolecule/kube-scheduler/notifier"
+	"gopkg.in/yaml.v2"
+)
+
+// Source supplies the jobList a scheduler.client runs.
+type Source interface {
+	Load() (jobList, error)
+}
+
+// Watcher is implemented by a Source whose jobList can change after Load, such as CRDSource.
+// client.Start calls Watch exactly once and republishes every call to onChange on Changes.
+type Watcher interface {
+	Watch(onChange func(jobList)) error
+}
+
+// NotifierSource is implemented by a Source that also carries notifier backend configuration,
+// such as YAMLFileSource's notifiers: block. A Source that doesn't implement it (e.g. CRDSource,
+// today) simply has no additional backends beyond the defaults every job already reaches.
+type NotifierSource interface {
+	NotifierConfigs() (map[string]notifier.BackendConfig, error)
+}
+
+// YAMLFileSource loads the schedule from a single YAML file on disk, e.g. schedule.yml baked into
+// a ConfigMap. It does not implement Watcher - picking up a change requires redeploying with a new
+// file.
+type YAMLFileSource struct {
+	Path string
+	Name string
+}
+
+// scheduleFile is the on-disk shape of a YAMLFileSource: job entries under `jobs:`, with an
+// optional `notifiers:` block naming the backends those jobs' `notify:` lists can refer to.
+type scheduleFile struct {
+	Notifiers map[string]notifier.BackendConfig `yaml:"notifiers"`
+	Jobs      jobList                           `yaml:"jobs"`
+}
+
+func (s YAMLFileSource) Load() (jobList, error) {
+	file, err := s.readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Jobs, nil
+}
+
+func (s YAMLFileSource) NotifierConfigs() (map[string]notifier.BackendConfig, error) {
+	file, err := s.readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Notifiers, nil
+}
+
+func (s YAMLFileSource) readFile() (scheduleFile, error) {
+	data, err := ioutil.ReadFile(fullPath(s.Path, s.Name))
+	if err != nil {
+		return scheduleFile{}, errors.Wrap(err, "Unable to open schedule config file")
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return scheduleFile{}, errors.Wrap(err, "Unable to unmarshal schedule yaml")
+	}
+
+	// Pre-notifiers: schedule.yml files are a bare top-level map[name]Job, with no jobs: key at
+	// all. Keep those working by falling back to a flat decode when jobs: isn't present, instead of
+	// silently unmarshaling them into an empty Jobs list.
+	if _, ok := raw["jobs"]; !ok {
+		var jobs jobList
+		if err := yaml.Unmarshal(data, &jobs); err != nil {
+			return scheduleFile{}, errors.Wrap(err, "Unable to unmarshal schedule yaml")
+		}
+
+		return scheduleFile{Jobs: jobs}, nil
+	}
+
+	var file scheduleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return scheduleFile{}, errors.Wrap(err, "Unable to unmarshal schedule yaml")
+	}
+
+	return file, nil
+}